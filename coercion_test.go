@@ -0,0 +1,157 @@
+package main
+
+import (
+	"testing"
+)
+
+// testFieldTypeDescriptor is a simple FieldTypeDescriptor backed by a map, used
+// throughout these tests to describe the fields available on a row
+type testFieldTypeDescriptor map[string]FieldType
+
+func (descriptor testFieldTypeDescriptor) FieldType(fieldName string) (fieldType FieldType, fieldExists bool) {
+	fieldType, fieldExists = descriptor[fieldName]
+	return
+}
+
+func stringToken(value string) *QueryToken {
+	return &QueryToken{value: value}
+}
+
+func newIdentifier(name string) *Identifier {
+	return &Identifier{identifier: stringToken(name)}
+}
+
+func newStringLiteral(value string) *StringLiteral {
+	return &StringLiteral{value: stringToken(value)}
+}
+
+func newEqualityBinaryExpression(lhs, rhs Expression) *BinaryExpression {
+	return &BinaryExpression{
+		lhs:      lhs,
+		rhs:      rhs,
+		operator: &Operator{operator: &QueryToken{tokenType: QtkCmpEq}},
+	}
+}
+
+func TestConvertStringToNumber(t *testing.T) {
+	tests := []struct {
+		value     string
+		converted bool
+	}{
+		{"5", true},
+		{"-3.14", true},
+		{"abc", false},
+		{"5abc", false},
+	}
+
+	for _, test := range tests {
+		_, converted := convertStringToNumber(newStringLiteral(test.value))
+		if converted != test.converted {
+			t.Errorf("convertStringToNumber(%q) converted = %v, expected %v", test.value, converted, test.converted)
+		}
+	}
+}
+
+func TestConvertStringToBool(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  bool
+		converted bool
+	}{
+		{"true", true, true},
+		{"FALSE", false, true},
+		{"yes", false, false},
+	}
+
+	for _, test := range tests {
+		boolLiteral, converted := convertStringToBool(newStringLiteral(test.value))
+		if converted != test.converted {
+			t.Errorf("convertStringToBool(%q) converted = %v, expected %v", test.value, converted, test.converted)
+		}
+
+		if converted && boolLiteral.value != test.expected {
+			t.Errorf("convertStringToBool(%q) = %v, expected %v", test.value, boolLiteral.value, test.expected)
+		}
+	}
+}
+
+// TestNumberComparisonCoercion verifies a quoted numeric literal compared against a
+// Number field is coerced to a NumberLiteral during Process, matching the scalar case
+func TestNumberComparisonCoercion(t *testing.T) {
+	fieldTypeDescriptor := testFieldTypeDescriptor{"count": FtNumber}
+	expression := newEqualityBinaryExpression(newIdentifier("count"), newStringLiteral("5"))
+
+	processor := NewExpressionProcessor(expression, fieldTypeDescriptor, NewDefaultFunctionRegistry())
+	if _, errors := processor.Process(); len(errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	if _, ok := expression.rhs.(*NumberLiteral); !ok {
+		t.Errorf("Expected rhs to be coerced to a NumberLiteral, got %T", expression.rhs)
+	}
+}
+
+// TestSetComparisonCoercionMatchesScalarComparison verifies that set membership
+// (IN) coerces its elements the same way a plain comparison against the same field
+// type does, for every field type set coercion supports
+func TestSetComparisonCoercionMatchesScalarComparison(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType FieldType
+		value     string
+		check     func(Expression) bool
+	}{
+		{"number", FtNumber, "5", func(e Expression) bool { _, ok := e.(*NumberLiteral); return ok }},
+		{"bool", FtBool, "true", func(e Expression) bool { _, ok := e.(*BoolLiteral); return ok }},
+		{"duration", FtDuration, "7d", func(e Expression) bool { _, ok := e.(*DurationLiteral); return ok }},
+		{"date", FtDate, "2024-01-01", func(e Expression) bool { _, ok := e.(*DateLiteral); return ok }},
+	}
+
+	for _, test := range tests {
+		fieldTypeDescriptor := testFieldTypeDescriptor{"field": test.fieldType}
+		setLiteral := &SetLiteral{values: []Expression{newStringLiteral(test.value)}}
+		binaryExpression := &BinaryExpression{
+			lhs:      newIdentifier("field"),
+			rhs:      setLiteral,
+			operator: &Operator{operator: &QueryToken{tokenType: QtkCmpIn}},
+		}
+
+		binaryExpression.processSetComparison(fieldTypeDescriptor)
+
+		if !test.check(setLiteral.values[0]) {
+			t.Errorf("%v: expected set element to be coerced, got %T", test.name, setLiteral.values[0])
+		}
+	}
+}
+
+// TestSetComparisonValidatesForEveryCoercibleFieldType verifies a field IN (...)
+// comparison passes Validate (not just ConvertTypes) for every field type
+// processSetComparison coerces - operatorAllowedOperandTypes must allow exactly
+// the same LHS types on IN/NOT IN that the coercion above supports
+func TestSetComparisonValidatesForEveryCoercibleFieldType(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType FieldType
+		value     string
+	}{
+		{"string", FtString, "alice"},
+		{"number", FtNumber, "5"},
+		{"date", FtDate, "2024-01-01"},
+		{"bool", FtBool, "true"},
+		{"duration", FtDuration, "7d"},
+	}
+
+	for _, test := range tests {
+		fieldTypeDescriptor := testFieldTypeDescriptor{"field": test.fieldType}
+		expression := &BinaryExpression{
+			lhs:      newIdentifier("field"),
+			rhs:      &SetLiteral{values: []Expression{newStringLiteral(test.value)}},
+			operator: &Operator{operator: &QueryToken{tokenType: QtkCmpIn}},
+		}
+
+		processor := NewExpressionProcessor(expression, fieldTypeDescriptor, NewDefaultFunctionRegistry())
+		if _, errors := processor.Process(); len(errors) > 0 {
+			t.Errorf("%v: unexpected errors: %v", test.name, errors)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConvertStringToDuration(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  time.Duration
+		converted bool
+	}{
+		{"7d", 7 * 24 * time.Hour, true},
+		{"24h", 24 * time.Hour, true},
+		{"30m", 30 * time.Minute, true},
+		{"10", 0, false},
+		{"7x", 0, false},
+	}
+
+	for _, test := range tests {
+		durationLiteral, converted := convertStringToDuration(newStringLiteral(test.value))
+		if converted != test.converted {
+			t.Errorf("convertStringToDuration(%q) converted = %v, expected %v", test.value, converted, test.converted)
+		}
+
+		if converted && durationLiteral.duration != test.expected {
+			t.Errorf("convertStringToDuration(%q) = %v, expected %v", test.value, durationLiteral.duration, test.expected)
+		}
+	}
+}
+
+// TestConvertStringToDateAcrossDSTTransition ensures dates either side of a DST
+// transition are interpreted correctly, since convertStringToDate constructs the
+// resulting time.Time in time.Local
+func TestConvertStringToDateAcrossDSTTransition(t *testing.T) {
+	original := time.Local
+	defer func() { time.Local = original }()
+
+	// America/New_York moves clocks forward on 2024-03-10
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Timezone database unavailable: %v", err)
+	}
+	time.Local = location
+
+	before, converted := convertStringToDate(newStringLiteral("2024-03-09"))
+	if !converted {
+		t.Fatalf("Expected 2024-03-09 to convert to a date")
+	}
+
+	after, converted := convertStringToDate(newStringLiteral("2024-03-11"))
+	if !converted {
+		t.Fatalf("Expected 2024-03-11 to convert to a date")
+	}
+
+	if !after.dateTime.After(before.dateTime) {
+		t.Errorf("Expected %v to be after %v across the DST transition", after.dateTime, before.dateTime)
+	}
+
+	if before.dateTime.Location() != time.Local || after.dateTime.Location() != time.Local {
+		t.Errorf("Expected converted dates to be in time.Local")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"1w", 7 * 24 * time.Hour, false},
+		{"24h", 24 * time.Hour, false},
+		{"", 0, true},
+		{"7", 0, true},
+		{"d7", 0, true},
+	}
+
+	for _, test := range tests {
+		duration, err := ParseDuration(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseDuration(%q) err = %v, wantErr %v", test.value, err, test.wantErr)
+			continue
+		}
+
+		if err == nil && duration != test.expected {
+			t.Errorf("ParseDuration(%q) = %v, expected %v", test.value, duration, test.expected)
+		}
+	}
+}
+
+// TestCompareValuesSupportsDurationOrdering verifies ordering comparisons between two
+// Duration values work at evaluation time - Validate accepts this for e.g.
+// AGE(a) > AGE(b) since it falls outside operatorAllowedOperandTypes and is treated
+// as an ordinary same-type comparison, so compareValues must handle it too
+func TestCompareValuesSupportsDurationOrdering(t *testing.T) {
+	expression := &BinaryExpression{
+		operator: &Operator{operator: &QueryToken{tokenType: QtkCmpGt}},
+	}
+
+	result, err := compareValues(expression, 2*time.Hour, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if matches, ok := result.(bool); !ok || !matches {
+		t.Errorf("Expected 2h > 1h to be true, got %v", result)
+	}
+
+	result, err = compareValues(expression, time.Hour, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if matches, ok := result.(bool); !ok || matches {
+		t.Errorf("Expected 1h > 2h to be false, got %v", result)
+	}
+}
+
+// TestNowDurationComparisonUsesProcessEvaluationTime verifies "now - 7d" folds to a
+// concrete DateLiteral using the evaluation time captured for that Process call
+func TestNowDurationComparisonUsesProcessEvaluationTime(t *testing.T) {
+	fieldTypeDescriptor := testFieldTypeDescriptor{"committerdate": FtDate}
+	nowExpr := &BinaryExpression{
+		lhs:      newIdentifier(nowIdentifierValue),
+		rhs:      &DurationLiteral{duration: 7 * 24 * time.Hour, durationToken: stringToken("7d")},
+		operator: &Operator{operator: &QueryToken{tokenType: QtkMinus}},
+	}
+	expression := &BinaryExpression{
+		lhs:      newIdentifier("committerdate"),
+		rhs:      nowExpr,
+		operator: &Operator{operator: &QueryToken{tokenType: QtkCmpGt}},
+	}
+
+	evaluationTime := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	ctx := &queryContext{
+		FieldTypeDescriptor: fieldTypeDescriptor,
+		evaluationTime:      evaluationTime,
+		functionRegistry:    NewDefaultFunctionRegistry(),
+	}
+
+	expression.ConvertTypes(ctx)
+
+	dateLiteral, ok := expression.rhs.(*DateLiteral)
+	if !ok {
+		t.Fatalf("Expected rhs to be folded into a DateLiteral, got %T", expression.rhs)
+	}
+
+	expected := evaluationTime.Add(-7 * 24 * time.Hour)
+	if !dateLiteral.dateTime.Equal(expected) {
+		t.Errorf("Expected now - 7d to resolve to %v, got %v", expected, dateLiteral.dateTime)
+	}
+}
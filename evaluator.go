@@ -0,0 +1,436 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	glob "github.com/gobwas/glob"
+)
+
+// FieldValueProvider supplies the value of a field for a single row (e.g. a commit)
+// being evaluated against a compiled query
+type FieldValueProvider interface {
+	FieldValue(fieldName string) (value interface{}, fieldType FieldType, exists bool)
+}
+
+// Evaluator is implemented by every expression that can be evaluated against a FieldValueProvider
+type Evaluator interface {
+	Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error)
+}
+
+// Evaluate returns the underlying date value
+func (dateLiteral *DateLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	return dateLiteral.dateTime, nil
+}
+
+// Evaluate returns the underlying duration value
+func (durationLiteral *DurationLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	return durationLiteral.duration, nil
+}
+
+// Evaluate returns the underlying boolean value
+func (boolLiteral *BoolLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	return boolLiteral.value, nil
+}
+
+// Evaluate returns the compiled regex
+func (regexLiteral *RegexLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	return regexLiteral.regex, nil
+}
+
+// Evaluate returns the compiled glob
+func (globLiteral *GlobLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	return globLiteral.glob, nil
+}
+
+// Evaluate returns the evaluated values of every element in the set
+func (setLiteral *SetLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	values := make([]interface{}, 0, len(setLiteral.values))
+
+	for _, value := range setLiteral.values {
+		evaluator, ok := value.(Evaluator)
+		if !ok {
+			return nil, GenerateExpressionError(value, "Set element cannot be evaluated")
+		}
+
+		evaluatedValue, err := evaluator.Evaluate(fieldValueProvider)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, evaluatedValue)
+	}
+
+	return values, nil
+}
+
+// Evaluate returns the underlying string value
+func (stringLiteral *StringLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	return stringLiteral.value.value, nil
+}
+
+// Evaluate returns the underlying number value
+func (numberLiteral *NumberLiteral) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	value, err := strconv.ParseFloat(numberLiteral.value.value, 64)
+	if err != nil {
+		return nil, GenerateExpressionError(numberLiteral, "Invalid number: %v", numberLiteral.value.value)
+	}
+
+	return value, nil
+}
+
+// Evaluate returns the value of the field this identifier refers to
+func (identifier *Identifier) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	value, _, exists := fieldValueProvider.FieldValue(identifier.identifier.value)
+	if !exists {
+		return nil, GenerateExpressionError(identifier, "No value available for field: %v", identifier.identifier.value)
+	}
+
+	return value, nil
+}
+
+// Evaluate defers evaluation to the parenthesized expression
+func (parenExpression *ParenExpression) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	evaluator, ok := parenExpression.expression.(Evaluator)
+	if !ok {
+		return nil, GenerateExpressionError(parenExpression, "Expression cannot be evaluated")
+	}
+
+	return evaluator.Evaluate(fieldValueProvider)
+}
+
+// Evaluate negates the boolean result of the child expression
+func (unaryExpression *UnaryExpression) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	evaluator, ok := unaryExpression.expression.(Evaluator)
+	if !ok {
+		return nil, GenerateExpressionError(unaryExpression, "Expression cannot be evaluated")
+	}
+
+	value, err := evaluator.Evaluate(fieldValueProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	boolValue, ok := value.(bool)
+	if !ok {
+		return nil, GenerateExpressionError(unaryExpression, "%v operator requires a boolean operand", unaryExpression.operator.operator.value)
+	}
+
+	return !boolValue, nil
+}
+
+// Evaluate resolves the condition and evaluates the matching branch
+func (conditionalExpression *ConditionalExpression) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	condEvaluator, ok := conditionalExpression.CondExpr.(Evaluator)
+	if !ok {
+		return nil, GenerateExpressionError(conditionalExpression, "Condition cannot be evaluated")
+	}
+
+	condValue, err := condEvaluator.Evaluate(fieldValueProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	condResult, ok := condValue.(bool)
+	if !ok {
+		return nil, GenerateExpressionError(conditionalExpression, "Condition of a conditional expression must evaluate to a boolean value")
+	}
+
+	branch := conditionalExpression.FalseExpr
+	if condResult {
+		branch = conditionalExpression.TrueExpr
+	}
+
+	evaluator, ok := branch.(Evaluator)
+	if !ok {
+		return nil, GenerateExpressionError(conditionalExpression, "Branch of a conditional expression cannot be evaluated")
+	}
+
+	return evaluator.Evaluate(fieldValueProvider)
+}
+
+// Evaluate invokes the registered function with its evaluated arguments
+func (functionCall *FunctionCall) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	if functionCall.resolvedFunction == nil {
+		return nil, GenerateExpressionError(functionCall, "Unknown function: %v", functionCall.name.value)
+	}
+
+	function := *functionCall.resolvedFunction
+
+	arguments := make([]interface{}, 0, len(functionCall.Arguments))
+
+	for _, argument := range functionCall.Arguments {
+		evaluator, ok := argument.(Evaluator)
+		if !ok {
+			return nil, GenerateExpressionError(argument, "Argument cannot be evaluated")
+		}
+
+		value, err := evaluator.Evaluate(fieldValueProvider)
+		if err != nil {
+			return nil, err
+		}
+
+		arguments = append(arguments, value)
+	}
+
+	if function.Execute == nil {
+		return nil, GenerateExpressionError(functionCall, "Function %v has no implementation", functionCall.name.value)
+	}
+
+	value, err := function.Execute(arguments)
+	if err != nil {
+		return nil, GenerateExpressionError(functionCall, "%v", err)
+	}
+
+	return value, nil
+}
+
+// Evaluate evaluates a logical (AND/OR) or comparison expression
+func (binaryExpression *BinaryExpression) Evaluate(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	if !binaryExpression.IsComparison() {
+		return binaryExpression.evaluateLogicalExpression(fieldValueProvider)
+	}
+
+	return binaryExpression.evaluateComparison(fieldValueProvider)
+}
+
+func (binaryExpression *BinaryExpression) evaluateLogicalExpression(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	lhsValue, err := evaluateOperand(binaryExpression.lhs, fieldValueProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	lhsBool, ok := lhsValue.(bool)
+	if !ok {
+		return nil, GenerateExpressionError(binaryExpression, "Operands of a logical operator must evaluate to boolean values")
+	}
+
+	switch strings.ToLower(binaryExpression.operator.operator.value) {
+	case "&&", "and":
+		if !lhsBool {
+			return false, nil
+		}
+	case "||", "or":
+		if lhsBool {
+			return true, nil
+		}
+	default:
+		return nil, GenerateExpressionError(binaryExpression, "Unknown logical operator: %v", binaryExpression.operator.operator.value)
+	}
+
+	rhsValue, err := evaluateOperand(binaryExpression.rhs, fieldValueProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	rhsBool, ok := rhsValue.(bool)
+	if !ok {
+		return nil, GenerateExpressionError(binaryExpression, "Operands of a logical operator must evaluate to boolean values")
+	}
+
+	return rhsBool, nil
+}
+
+func (binaryExpression *BinaryExpression) evaluateComparison(fieldValueProvider FieldValueProvider) (interface{}, error) {
+	lhsValue, err := evaluateOperand(binaryExpression.lhs, fieldValueProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	rhsValue, err := evaluateOperand(binaryExpression.rhs, fieldValueProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	switch binaryExpression.operator.operator.tokenType {
+	case QtkCmpGlob:
+		globValue, ok := rhsValue.(glob.Glob)
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "RHS of glob comparison must be a compiled glob")
+		}
+
+		lhsString, ok := lhsValue.(string)
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "LHS of glob comparison must be a string value")
+		}
+
+		return globValue.Match(lhsString), nil
+	case QtkCmpRegexp:
+		regexValue, ok := rhsValue.(interface{ MatchString(string) bool })
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "RHS of regex comparison must be a compiled regex")
+		}
+
+		lhsString, ok := lhsValue.(string)
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "LHS of regex comparison must be a string value")
+		}
+
+		return regexValue.MatchString(lhsString), nil
+	case QtkCmpIn, QtkCmpNotIn:
+		values, ok := rhsValue.([]interface{})
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "RHS of IN/NOT IN comparison must be a set")
+		}
+
+		isMember := false
+		for _, value := range values {
+			if valuesEqual(lhsValue, value) {
+				isMember = true
+				break
+			}
+		}
+
+		if binaryExpression.operator.operator.tokenType == QtkCmpNotIn {
+			return !isMember, nil
+		}
+
+		return isMember, nil
+	case QtkCmpEq:
+		return valuesEqual(lhsValue, rhsValue), nil
+	case QtkCmpNe:
+		return !valuesEqual(lhsValue, rhsValue), nil
+	case QtkCmpGt, QtkCmpGe, QtkCmpLt, QtkCmpLe:
+		return compareValues(binaryExpression, lhsValue, rhsValue)
+	default:
+		return nil, GenerateExpressionError(binaryExpression, "Unknown comparison operator: %v", binaryExpression.operator.operator.value)
+	}
+}
+
+func evaluateOperand(expression Expression, fieldValueProvider FieldValueProvider) (interface{}, error) {
+	evaluator, ok := expression.(Evaluator)
+	if !ok {
+		return nil, GenerateExpressionError(expression, "Expression cannot be evaluated")
+	}
+
+	return evaluator.Evaluate(fieldValueProvider)
+}
+
+func valuesEqual(lhsValue, rhsValue interface{}) bool {
+	if lhsDate, ok := lhsValue.(time.Time); ok {
+		if rhsDate, ok := rhsValue.(time.Time); ok {
+			return lhsDate.Equal(rhsDate)
+		}
+	}
+
+	return lhsValue == rhsValue
+}
+
+func compareValues(binaryExpression *BinaryExpression, lhsValue, rhsValue interface{}) (interface{}, error) {
+	var comparison int
+
+	switch lhs := lhsValue.(type) {
+	case time.Time:
+		rhs, ok := rhsValue.(time.Time)
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "Cannot compare a date value with a non-date value")
+		}
+
+		switch {
+		case lhs.Before(rhs):
+			comparison = -1
+		case lhs.After(rhs):
+			comparison = 1
+		default:
+			comparison = 0
+		}
+	case float64:
+		rhs, ok := rhsValue.(float64)
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "Cannot compare a number value with a non-number value")
+		}
+
+		switch {
+		case lhs < rhs:
+			comparison = -1
+		case lhs > rhs:
+			comparison = 1
+		default:
+			comparison = 0
+		}
+	case time.Duration:
+		rhs, ok := rhsValue.(time.Duration)
+		if !ok {
+			return nil, GenerateExpressionError(binaryExpression, "Cannot compare a duration value with a non-duration value")
+		}
+
+		switch {
+		case lhs < rhs:
+			comparison = -1
+		case lhs > rhs:
+			comparison = 1
+		default:
+			comparison = 0
+		}
+	default:
+		return nil, GenerateExpressionError(binaryExpression, "Ordering comparisons are only supported for number, date and duration values")
+	}
+
+	switch binaryExpression.operator.operator.tokenType {
+	case QtkCmpGt:
+		return comparison > 0, nil
+	case QtkCmpGe:
+		return comparison >= 0, nil
+	case QtkCmpLt:
+		return comparison < 0, nil
+	case QtkCmpLe:
+		return comparison <= 0, nil
+	default:
+		return nil, GenerateExpressionError(binaryExpression, "Unknown comparison operator: %v", binaryExpression.operator.operator.value)
+	}
+}
+
+// CompiledQuery is a parsed and type-checked query expression that can be evaluated
+// against rows from an arbitrary data source
+type CompiledQuery struct {
+	expression Expression
+}
+
+// Evaluate evaluates the compiled query against the provided row and returns the boolean result
+func (compiledQuery CompiledQuery) Evaluate(row FieldValueProvider) (bool, error) {
+	evaluator, ok := compiledQuery.expression.(Evaluator)
+	if !ok {
+		return false, fmt.Errorf("Compiled query expression cannot be evaluated")
+	}
+
+	value, err := evaluator.Evaluate(row)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("Query did not evaluate to a boolean value")
+	}
+
+	return result, nil
+}
+
+// Compile parses, type checks and compiles the provided query string, returning a
+// CompiledQuery that can be evaluated against rows from an arbitrary data source.
+// This allows callers outside this package, such as custom views, to reuse the
+// query language without going through the grv view filtering code
+func Compile(queryString string, fieldTypeDescriptor FieldTypeDescriptor) (compiledQuery CompiledQuery, err error) {
+	scanner := NewQueryScanner(strings.NewReader(queryString))
+	parser := NewQueryParser(scanner)
+
+	expression, parseErr := parser.Parse()
+	if parseErr != nil {
+		return CompiledQuery{}, parseErr
+	}
+
+	expressionProcessor := NewExpressionProcessor(expression, fieldTypeDescriptor, NewDefaultFunctionRegistry())
+	processedExpression, errors := expressionProcessor.Process()
+	if len(errors) > 0 {
+		messages := make([]string, len(errors))
+		for index, validationError := range errors {
+			messages[index] = validationError.Error()
+		}
+
+		return CompiledQuery{}, fmt.Errorf("%v", strings.Join(messages, "; "))
+	}
+
+	return CompiledQuery{expression: processedExpression}, nil
+}
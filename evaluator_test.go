@@ -0,0 +1,183 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	glob "github.com/gobwas/glob"
+)
+
+// testFieldValueProvider is a simple FieldValueProvider backed by a map, used to
+// supply row field values in these tests
+type testFieldValueProvider map[string]interface{}
+
+func (provider testFieldValueProvider) FieldValue(fieldName string) (value interface{}, fieldType FieldType, exists bool) {
+	value, exists = provider[fieldName]
+	return
+}
+
+// restrictedFunctionRegistry has no functions registered, used to prove a
+// FunctionCall resolved against one registry is unaffected by a later query
+// being processed against a different registry
+type restrictedFunctionRegistry struct{}
+
+func (restrictedFunctionRegistry) Function(name string) (function Function, exists bool) {
+	return
+}
+
+func newFunctionCall(name string, arguments ...Expression) *FunctionCall {
+	return &FunctionCall{name: stringToken(name), Arguments: arguments}
+}
+
+func TestCompiledQueryEvaluate(t *testing.T) {
+	fieldTypeDescriptor := testFieldTypeDescriptor{"summary": FtString}
+	expression := newEqualityBinaryExpression(newIdentifier("summary"), newStringLiteral("hello"))
+
+	processor := NewExpressionProcessor(expression, fieldTypeDescriptor, NewDefaultFunctionRegistry())
+	processedExpression, errors := processor.Process()
+	if len(errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	compiledQuery := CompiledQuery{expression: processedExpression}
+
+	matches, err := compiledQuery.Evaluate(testFieldValueProvider{"summary": "hello"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if !matches {
+		t.Errorf("Expected row with summary \"hello\" to match")
+	}
+
+	matches, err = compiledQuery.Evaluate(testFieldValueProvider{"summary": "goodbye"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if matches {
+		t.Errorf("Expected row with summary \"goodbye\" not to match")
+	}
+}
+
+// TestFunctionCallResolutionDoesNotLeakBetweenQueries guards against the function
+// registry a FunctionCall resolves against leaking between independently compiled
+// queries - previously this was read from a package-level global at Evaluate time,
+// so compiling a second query with a different registry would silently change the
+// behaviour of a query compiled and evaluated earlier
+func TestFunctionCallResolutionDoesNotLeakBetweenQueries(t *testing.T) {
+	functionCallA := newFunctionCall("LENGTH", newStringLiteral("hello"))
+	ctxA := &queryContext{
+		FieldTypeDescriptor: testFieldTypeDescriptor{},
+		evaluationTime:      time.Now(),
+		functionRegistry:    NewDefaultFunctionRegistry(),
+	}
+	functionCallA.ConvertTypes(ctxA)
+
+	// Compiling a second query against a registry that doesn't know about LENGTH
+	// must not affect functionCallA, which already resolved its own function
+	functionCallB := newFunctionCall("LENGTH", newStringLiteral("world"))
+	ctxB := &queryContext{
+		FieldTypeDescriptor: testFieldTypeDescriptor{},
+		evaluationTime:      time.Now(),
+		functionRegistry:    restrictedFunctionRegistry{},
+	}
+	functionCallB.ConvertTypes(ctxB)
+
+	value, err := functionCallA.Evaluate(testFieldValueProvider{})
+	if err != nil {
+		t.Fatalf("Unexpected error evaluating functionCallA after functionCallB resolved against a different registry: %v", err)
+	}
+
+	if value.(float64) != 5 {
+		t.Errorf("Expected LENGTH(\"hello\") to evaluate to 5, got %v", value)
+	}
+
+	if _, err := functionCallB.Evaluate(testFieldValueProvider{}); err == nil {
+		t.Errorf("Expected functionCallB to fail to evaluate since LENGTH isn't registered on its registry")
+	}
+}
+
+// TestComparisonConvertsFunctionCallOperand guards against a comparison operand that
+// is itself a FunctionCall (e.g. LENGTH(summary) > 5) being skipped by ConvertTypes -
+// previously BinaryExpression.ConvertTypes only recursed into lhs/rhs for non-comparison
+// operators, so a FunctionCall operand never had its function resolved and cached,
+// causing Evaluate to always report the function as unknown
+func TestComparisonConvertsFunctionCallOperand(t *testing.T) {
+	fieldTypeDescriptor := testFieldTypeDescriptor{"summary": FtString}
+	expression := &BinaryExpression{
+		lhs:      newFunctionCall("LENGTH", newIdentifier("summary")),
+		rhs:      &NumberLiteral{value: stringToken("5")},
+		operator: &Operator{operator: &QueryToken{tokenType: QtkCmpGt}},
+	}
+
+	processor := NewExpressionProcessor(expression, fieldTypeDescriptor, NewDefaultFunctionRegistry())
+	processedExpression, errors := processor.Process()
+	if len(errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	compiledQuery := CompiledQuery{expression: processedExpression}
+
+	matches, err := compiledQuery.Evaluate(testFieldValueProvider{"summary": "hello world"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if !matches {
+		t.Errorf("Expected LENGTH(\"hello world\") > 5 to match")
+	}
+}
+
+// TestEvaluateReturnsErrorNotPanicOnFieldTypeMismatch guards against a panic when a
+// caller-supplied FieldValueProvider returns a value that doesn't match the type its
+// FieldTypeDescriptor declared for that field - an easy mistake for an external
+// integrator to make, and Compile/Evaluate is the public API such integrators use
+func TestEvaluateReturnsErrorNotPanicOnFieldTypeMismatch(t *testing.T) {
+	fieldTypeDescriptor := testFieldTypeDescriptor{"summary": FtString}
+
+	compiledGlob, err := glob.Compile("hello*")
+	if err != nil {
+		t.Fatalf("Unexpected error compiling glob: %v", err)
+	}
+
+	expression := &BinaryExpression{
+		lhs:      newIdentifier("summary"),
+		rhs:      &GlobLiteral{glob: compiledGlob, globString: stringToken("hello*")},
+		operator: &Operator{operator: &QueryToken{tokenType: QtkCmpGlob}},
+	}
+
+	if _, err := expression.Evaluate(testFieldValueProvider{"summary": 42}); err == nil {
+		t.Errorf("Expected a non-string summary value to produce an error, not a panic or silent success")
+	}
+
+	functionCall := newFunctionCall("LENGTH", newIdentifier("summary"))
+	ctx := &queryContext{
+		FieldTypeDescriptor: fieldTypeDescriptor,
+		evaluationTime:      time.Now(),
+		functionRegistry:    NewDefaultFunctionRegistry(),
+	}
+	functionCall.ConvertTypes(ctx)
+
+	if _, err := functionCall.Evaluate(testFieldValueProvider{"summary": 42}); err == nil {
+		t.Errorf("Expected LENGTH() called with a non-string value to produce an error, not a panic or silent success")
+	}
+}
+
+// TestAgeReflectsCurrentTimeNotEvaluationTime verifies AGE() is computed against
+// wall-clock time at evaluation, not a time value cached from when the query was
+// processed - a CompiledQuery may be evaluated long after it was compiled
+func TestAgeReflectsCurrentTimeNotEvaluationTime(t *testing.T) {
+	functionCall := newFunctionCall("AGE", newIdentifier("committerdate"))
+	ctx := &queryContext{
+		FieldTypeDescriptor: testFieldTypeDescriptor{"committerdate": FtDate},
+		evaluationTime:      time.Now().Add(-time.Hour),
+		functionRegistry:    NewDefaultFunctionRegistry(),
+	}
+	functionCall.ConvertTypes(ctx)
+
+	commitTime := time.Now().Add(-time.Minute)
+	value, err := functionCall.Evaluate(testFieldValueProvider{"committerdate": commitTime})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	age := value.(time.Duration)
+	if age < 0 || age > time.Minute+time.Second {
+		t.Errorf("Expected AGE() to reflect time.Now(), got an age of %v", age)
+	}
+}
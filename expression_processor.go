@@ -17,32 +17,97 @@ type FieldTypeDescriptor interface {
 	FieldType(fieldName string) (fieldType FieldType, fieldExists bool)
 }
 
+// Function describes the signature and implementation of a function that can be
+// called from within a query expression
+type Function struct {
+	ArgumentTypes []FieldType
+	ReturnType    FieldType
+
+	// Execute implements the function's behaviour, receiving already-evaluated
+	// argument values in declaration order. This is what makes a custom
+	// FunctionRegistry genuinely pluggable - a caller-supplied registry provides its
+	// own Execute rather than this package having to know its name in advance
+	Execute func(arguments []interface{}) (interface{}, error)
+}
+
+// FunctionRegistry provides lookup of the functions available for use within query expressions
+type FunctionRegistry interface {
+	Function(name string) (function Function, exists bool)
+}
+
+// QueryContext extends FieldTypeDescriptor with the process-scoped state needed to
+// resolve functions and fold now-relative date comparisons. ExpressionProcessor always
+// passes a value satisfying this interface to ConvertTypes/Validate, so expressions
+// that need this state recover it via a type assertion on the FieldTypeDescriptor they
+// are given rather than a package-level global. This keeps a CompiledQuery (see
+// evaluator.go) safe to compile and evaluate independently of any other query that may
+// be compiled or evaluated concurrently or in between
+type QueryContext interface {
+	FieldTypeDescriptor
+	EvaluationTime() time.Time
+	FunctionRegistry() FunctionRegistry
+}
+
+// queryContext is the concrete QueryContext built once per ExpressionProcessor.Process call
+type queryContext struct {
+	FieldTypeDescriptor
+	evaluationTime   time.Time
+	functionRegistry FunctionRegistry
+}
+
+// EvaluationTime returns the time this query's now-relative expressions are evaluated against
+func (ctx *queryContext) EvaluationTime() time.Time {
+	return ctx.evaluationTime
+}
+
+// FunctionRegistry returns the set of functions available to this query
+func (ctx *queryContext) FunctionRegistry() FunctionRegistry {
+	return ctx.functionRegistry
+}
+
 // ExpressionProcessor takes the query expression that has been parsed and processes it further
 // Type conversion and validation of the expression are performed
 type ExpressionProcessor struct {
 	expression          Expression
 	fieldTypeDescriptor FieldTypeDescriptor
+	functionRegistry    FunctionRegistry
 }
 
 // NewExpressionProcessor creates an expression processor instance for the provided expression
-func NewExpressionProcessor(expression Expression, fieldTypeDescriptor FieldTypeDescriptor) *ExpressionProcessor {
+func NewExpressionProcessor(expression Expression, fieldTypeDescriptor FieldTypeDescriptor, functionRegistry FunctionRegistry) *ExpressionProcessor {
 	return &ExpressionProcessor{
 		expression:          expression,
 		fieldTypeDescriptor: fieldTypeDescriptor,
+		functionRegistry:    functionRegistry,
 	}
 }
 
 // Process performs type conversion and validates the expression
 func (expressionProcessor *ExpressionProcessor) Process() (expression Expression, errors []error) {
-	if logicalExpression, ok := expressionProcessor.expression.(LogicalExpression); ok {
-		logicalExpression.ConvertTypes(expressionProcessor.fieldTypeDescriptor)
-		errors = logicalExpression.Validate(expressionProcessor.fieldTypeDescriptor)
-		expression = logicalExpression
-	} else {
+	ctx := &queryContext{
+		FieldTypeDescriptor: expressionProcessor.fieldTypeDescriptor,
+		evaluationTime:      time.Now(),
+		functionRegistry:    expressionProcessor.functionRegistry,
+	}
+
+	logicalExpression, ok := expressionProcessor.expression.(LogicalExpression)
+	if !ok {
 		errors = append(errors, fmt.Errorf("Expected logical expression but received expression of type %v",
 			reflect.TypeOf(expressionProcessor.expression).Elem().Name()))
+		return
+	}
+
+	logicalExpression.ConvertTypes(ctx)
+
+	if !logicalExpression.IsBoolean(ctx) {
+		errors = append(errors, fmt.Errorf("Expected query to resolve to a boolean value but received expression of type %v",
+			reflect.TypeOf(expressionProcessor.expression).Elem().Name()))
+		return
 	}
 
+	errors = logicalExpression.Validate(ctx)
+	expression = logicalExpression
+
 	return
 }
 
@@ -70,6 +135,40 @@ var operatorAllowedOperandTypes = map[QueryTokenType]map[binaryOperatorPosition]
 			FtRegex: true,
 		},
 	},
+	QtkCmpIn: {
+		bopLeft: {
+			FtString:   true,
+			FtNumber:   true,
+			FtDate:     true,
+			FtBool:     true,
+			FtDuration: true,
+		},
+		bopRight: {
+			FtSet: true,
+		},
+	},
+	QtkCmpNotIn: {
+		bopLeft: {
+			FtString:   true,
+			FtNumber:   true,
+			FtDate:     true,
+			FtBool:     true,
+			FtDuration: true,
+		},
+		bopRight: {
+			FtSet: true,
+		},
+	},
+}
+
+// isSetOperator returns true if the operator is a set membership operator (IN/NOT IN)
+func (operator *Operator) isSetOperator() bool {
+	switch operator.operator.tokenType {
+	case QtkCmpIn, QtkCmpNotIn:
+		return true
+	default:
+		return false
+	}
 }
 
 func (operator *Operator) isOperandTypeRestricted() bool {
@@ -118,6 +217,35 @@ const (
 
 var dateFormatPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
 var dateTimeFormatPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$`)
+var durationPattern = regexp.MustCompile(`^(\d+)([smhdw])$`)
+
+// durationUnits maps a duration literal's unit suffix to its equivalent time.Duration
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// nowIdentifierValue is the reserved identifier representing the current time
+// when combined with a DurationLiteral, e.g. now - 7d
+const nowIdentifierValue = "now"
+
+// ParseDuration converts a duration literal value, e.g. "7d" or "24h", into a time.Duration
+func ParseDuration(value string) (duration time.Duration, err error) {
+	matches := durationPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, fmt.Errorf("Invalid duration: %v", value)
+	}
+
+	var quantity int
+	if _, err = fmt.Sscanf(matches[1], "%d", &quantity); err != nil {
+		return 0, fmt.Errorf("Invalid duration: %v", value)
+	}
+
+	return time.Duration(quantity) * durationUnits[matches[2]], nil
+}
 
 // FieldType represents the data type of a field
 type FieldType int
@@ -130,15 +258,21 @@ const (
 	FtDate
 	FtGlob
 	FtRegex
+	FtSet
+	FtDuration
+	FtBool
 )
 
 var fieldTypeNames = map[FieldType]string{
-	FtInvalid: "Invalid",
-	FtString:  "String",
-	FtNumber:  "Number",
-	FtDate:    "Date",
-	FtGlob:    "Glob",
-	FtRegex:   "Regex",
+	FtInvalid:  "Invalid",
+	FtString:   "String",
+	FtNumber:   "Number",
+	FtDate:     "Date",
+	FtGlob:     "Glob",
+	FtRegex:    "Regex",
+	FtSet:      "Set",
+	FtDuration: "Duration",
+	FtBool:     "Bool",
 }
 
 // TypeDescriptor returns the type of a field or value
@@ -177,6 +311,68 @@ func (dateLiteral *DateLiteral) FieldType(fieldTypeDescriptor FieldTypeDescripto
 	return FtDate
 }
 
+// DurationLiteral represents a relative time span, e.g. 7d or 24h
+type DurationLiteral struct {
+	duration      time.Duration
+	durationToken *QueryToken
+}
+
+// Equal returns true if the provided expression is equal
+func (durationLiteral *DurationLiteral) Equal(expression Expression) bool {
+	other, ok := expression.(*DurationLiteral)
+	if !ok {
+		return false
+	}
+
+	return durationLiteral.duration == other.duration
+}
+
+// String returns the string representation of this duration
+func (durationLiteral *DurationLiteral) String() string {
+	return durationLiteral.durationToken.value
+}
+
+// Pos returns the position this duration appeared at in the input stream
+func (durationLiteral *DurationLiteral) Pos() QueryScannerPos {
+	return durationLiteral.durationToken.startPos
+}
+
+// FieldType returns the data type of this value
+func (durationLiteral *DurationLiteral) FieldType(fieldTypeDescriptor FieldTypeDescriptor) FieldType {
+	return FtDuration
+}
+
+// BoolLiteral represents a boolean value
+type BoolLiteral struct {
+	value      bool
+	boolString *QueryToken
+}
+
+// Equal returns true if the provided expression is equal
+func (boolLiteral *BoolLiteral) Equal(expression Expression) bool {
+	other, ok := expression.(*BoolLiteral)
+	if !ok {
+		return false
+	}
+
+	return boolLiteral.value == other.value
+}
+
+// String returns the string representation of this boolean value
+func (boolLiteral *BoolLiteral) String() string {
+	return boolLiteral.boolString.value
+}
+
+// Pos returns the position this boolean value appeared at in the input stream
+func (boolLiteral *BoolLiteral) Pos() QueryScannerPos {
+	return boolLiteral.boolString.startPos
+}
+
+// FieldType returns the data type of this value
+func (boolLiteral *BoolLiteral) FieldType(fieldTypeDescriptor FieldTypeDescriptor) FieldType {
+	return FtBool
+}
+
 // RegexLiteral represents a regex value
 type RegexLiteral struct {
 	regex       *regexp.Regexp
@@ -239,6 +435,52 @@ func (globLiteral *GlobLiteral) FieldType(fieldTypeDescriptor FieldTypeDescripto
 	return FtGlob
 }
 
+// SetLiteral represents a parenthesized list of literal values used as the
+// right hand side of an IN/NOT IN comparison, e.g. ("alice", "bob")
+type SetLiteral struct {
+	values    []Expression
+	openParen *QueryToken
+}
+
+// Equal returns true if the provided expression is equal
+func (setLiteral *SetLiteral) Equal(expression Expression) bool {
+	other, ok := expression.(*SetLiteral)
+	if !ok || len(setLiteral.values) != len(other.values) {
+		return false
+	}
+
+	for index, value := range setLiteral.values {
+		if !value.Equal(other.values[index]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the string representation of the set
+func (setLiteral *SetLiteral) String() string {
+	var values []string
+
+	for _, value := range setLiteral.values {
+		if stringer, ok := value.(fmt.Stringer); ok {
+			values = append(values, stringer.String())
+		}
+	}
+
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+// Pos returns the position this set appeared at in the input stream
+func (setLiteral *SetLiteral) Pos() QueryScannerPos {
+	return setLiteral.openParen.startPos
+}
+
+// FieldType returns the data type of this value
+func (setLiteral *SetLiteral) FieldType(fieldTypeDescriptor FieldTypeDescriptor) FieldType {
+	return FtSet
+}
+
 // FieldType returns the data type of this value
 func (stringLiteral *StringLiteral) FieldType(fieldTypeDescriptor FieldTypeDescriptor) FieldType {
 	return FtString
@@ -272,11 +514,44 @@ type ValidatableExpression interface {
 	Validate(FieldTypeDescriptor) []error
 }
 
-// LogicalExpression is an expression which resolves to a boolean value and is composed of child expressions
+// LogicalExpression is an expression which is composed of child expressions and may resolve
+// to a boolean value. Implementing this interface is not on its own proof that an expression
+// resolves to boolean - e.g. FunctionCall and ConditionalExpression also implement it but can
+// resolve to any type - so callers that require a boolean value must also check IsBoolean
 type LogicalExpression interface {
 	Expression
 	ValidatableExpression
 	ConvertTypes(FieldTypeDescriptor)
+	IsBoolean(FieldTypeDescriptor) bool
+}
+
+// isExpressionBoolean returns true if the provided expression resolves to a boolean value,
+// whether that's a value type reporting FtBool through TypeDescriptor or a LogicalExpression
+// confirming it via IsBoolean
+func isExpressionBoolean(expression Expression, fieldTypeDescriptor FieldTypeDescriptor) bool {
+	if typeDescriptor, ok := expression.(TypeDescriptor); ok {
+		return typeDescriptor.FieldType(fieldTypeDescriptor) == FtBool
+	}
+
+	if logicalExpression, ok := expression.(LogicalExpression); ok {
+		return logicalExpression.IsBoolean(fieldTypeDescriptor)
+	}
+
+	return false
+}
+
+// determineBranchType determines the type of a conditional expression branch, treating a
+// boolean-resolving expression that isn't a TypeDescriptor (e.g. a bare comparison) as FtBool
+func determineBranchType(expression Expression, fieldTypeDescriptor FieldTypeDescriptor) (fieldType FieldType, isValueType bool) {
+	if fieldType, isValueType = determineFieldType(expression, fieldTypeDescriptor); isValueType {
+		return
+	}
+
+	if isExpressionBoolean(expression, fieldTypeDescriptor) {
+		return FtBool, true
+	}
+
+	return
 }
 
 // GenerateExpressionError generates an error with expression position information included
@@ -298,7 +573,7 @@ func (parenExpression *ParenExpression) ConvertTypes(fieldTypeDescriptor FieldTy
 
 // Validate checks the child expression is valid
 func (parenExpression *ParenExpression) Validate(fieldTypeDescriptor FieldTypeDescriptor) (errors []error) {
-	if _, ok := parenExpression.expression.(LogicalExpression); !ok {
+	if !isExpressionBoolean(parenExpression.expression, fieldTypeDescriptor) {
 		errors = append(errors, GenerateExpressionError(parenExpression, "Expression in parentheses must resolve to a boolean value"))
 	}
 
@@ -309,6 +584,11 @@ func (parenExpression *ParenExpression) Validate(fieldTypeDescriptor FieldTypeDe
 	return
 }
 
+// IsBoolean returns true if the expression in parentheses resolves to a boolean value
+func (parenExpression *ParenExpression) IsBoolean(fieldTypeDescriptor FieldTypeDescriptor) bool {
+	return isExpressionBoolean(parenExpression.expression, fieldTypeDescriptor)
+}
+
 // ConvertTypes defers the call to the child expression
 func (unaryExpression *UnaryExpression) ConvertTypes(fieldTypeDescriptor FieldTypeDescriptor) {
 	if logicalExpression, ok := unaryExpression.expression.(LogicalExpression); ok {
@@ -318,7 +598,7 @@ func (unaryExpression *UnaryExpression) ConvertTypes(fieldTypeDescriptor FieldTy
 
 // Validate checks the child expression is valid
 func (unaryExpression *UnaryExpression) Validate(fieldTypeDescriptor FieldTypeDescriptor) (errors []error) {
-	if _, ok := unaryExpression.expression.(LogicalExpression); !ok {
+	if !isExpressionBoolean(unaryExpression.expression, fieldTypeDescriptor) {
 		errors = append(errors, GenerateExpressionError(unaryExpression,
 			"%v operator can only be applied to expressions that resolve to a boolean value",
 			unaryExpression.operator.operator.value))
@@ -331,32 +611,488 @@ func (unaryExpression *UnaryExpression) Validate(fieldTypeDescriptor FieldTypeDe
 	return
 }
 
-// ConvertTypes defers the call to the child expressions if they're logical
-// Otherwise performs type conversion on the child expressions if necessary
-func (binaryExpression *BinaryExpression) ConvertTypes(fieldTypeDescriptor FieldTypeDescriptor) {
-	if !binaryExpression.IsComparison() {
-		if logicalExpression, ok := binaryExpression.lhs.(LogicalExpression); ok {
-			logicalExpression.ConvertTypes(fieldTypeDescriptor)
+// IsBoolean returns true, since a unary operator can only be applied to and always
+// produces a boolean value
+func (unaryExpression *UnaryExpression) IsBoolean(fieldTypeDescriptor FieldTypeDescriptor) bool {
+	return true
+}
+
+// ConditionalExpression represents a ternary conditional expression of the form
+// CondExpr ? TrueExpr : FalseExpr
+type ConditionalExpression struct {
+	CondExpr     Expression
+	TrueExpr     Expression
+	FalseExpr    Expression
+	questionMark *QueryToken
+}
+
+// Equal returns true if the provided expression is equal
+func (conditionalExpression *ConditionalExpression) Equal(expression Expression) bool {
+	other, ok := expression.(*ConditionalExpression)
+	if !ok {
+		return false
+	}
+
+	return conditionalExpression.CondExpr.Equal(other.CondExpr) &&
+		conditionalExpression.TrueExpr.Equal(other.TrueExpr) &&
+		conditionalExpression.FalseExpr.Equal(other.FalseExpr)
+}
+
+// String returns the string representation of this conditional expression
+func (conditionalExpression *ConditionalExpression) String() string {
+	var buffer bytes.Buffer
+
+	if stringer, ok := conditionalExpression.CondExpr.(fmt.Stringer); ok {
+		buffer.WriteString(stringer.String())
+	}
+
+	buffer.WriteString(" ? ")
+
+	if stringer, ok := conditionalExpression.TrueExpr.(fmt.Stringer); ok {
+		buffer.WriteString(stringer.String())
+	}
+
+	buffer.WriteString(" : ")
+
+	if stringer, ok := conditionalExpression.FalseExpr.(fmt.Stringer); ok {
+		buffer.WriteString(stringer.String())
+	}
+
+	return buffer.String()
+}
+
+// Pos returns the position the condition expression appeared at in the input stream
+func (conditionalExpression *ConditionalExpression) Pos() QueryScannerPos {
+	return conditionalExpression.CondExpr.Pos()
+}
+
+// ConvertTypes defers the call to the condition and branch expressions
+func (conditionalExpression *ConditionalExpression) ConvertTypes(fieldTypeDescriptor FieldTypeDescriptor) {
+	if logicalExpression, ok := conditionalExpression.CondExpr.(LogicalExpression); ok {
+		logicalExpression.ConvertTypes(fieldTypeDescriptor)
+	}
+
+	if logicalExpression, ok := conditionalExpression.TrueExpr.(LogicalExpression); ok {
+		logicalExpression.ConvertTypes(fieldTypeDescriptor)
+	}
+
+	if logicalExpression, ok := conditionalExpression.FalseExpr.(LogicalExpression); ok {
+		logicalExpression.ConvertTypes(fieldTypeDescriptor)
+	}
+}
+
+// Validate checks CondExpr resolves to a boolean value and TrueExpr/FalseExpr have compatible types
+func (conditionalExpression *ConditionalExpression) Validate(fieldTypeDescriptor FieldTypeDescriptor) (errors []error) {
+	if !isExpressionBoolean(conditionalExpression.CondExpr, fieldTypeDescriptor) {
+		errors = append(errors, GenerateExpressionError(conditionalExpression, "Condition of a conditional expression must resolve to a boolean value"))
+	}
+
+	if validatableExpression, ok := conditionalExpression.CondExpr.(ValidatableExpression); ok {
+		errors = append(errors, validatableExpression.Validate(fieldTypeDescriptor)...)
+	}
+
+	if validatableExpression, ok := conditionalExpression.TrueExpr.(ValidatableExpression); ok {
+		errors = append(errors, validatableExpression.Validate(fieldTypeDescriptor)...)
+	}
+
+	if validatableExpression, ok := conditionalExpression.FalseExpr.(ValidatableExpression); ok {
+		errors = append(errors, validatableExpression.Validate(fieldTypeDescriptor)...)
+	}
+
+	trueType, isTrueValueType := determineBranchType(conditionalExpression.TrueExpr, fieldTypeDescriptor)
+	falseType, isFalseValueType := determineBranchType(conditionalExpression.FalseExpr, fieldTypeDescriptor)
+
+	if isTrueValueType && isFalseValueType && trueType != falseType && !(trueType == FtInvalid || falseType == FtInvalid) {
+		errors = append(errors, GenerateExpressionError(conditionalExpression,
+			"Branches of a conditional expression must have compatible types - True branch Type: %v vs False branch Type: %v",
+			fieldTypeNames[trueType], fieldTypeNames[falseType]))
+	}
+
+	return
+}
+
+// IsBoolean returns true if both branches of this conditional expression resolve to boolean values
+func (conditionalExpression *ConditionalExpression) IsBoolean(fieldTypeDescriptor FieldTypeDescriptor) bool {
+	return isExpressionBoolean(conditionalExpression.TrueExpr, fieldTypeDescriptor) &&
+		isExpressionBoolean(conditionalExpression.FalseExpr, fieldTypeDescriptor)
+}
+
+// FieldType returns the data type this conditional expression resolves to
+func (conditionalExpression *ConditionalExpression) FieldType(fieldTypeDescriptor FieldTypeDescriptor) FieldType {
+	if trueType, isValueType := determineBranchType(conditionalExpression.TrueExpr, fieldTypeDescriptor); isValueType && trueType != FtInvalid {
+		return trueType
+	}
+
+	if falseType, isValueType := determineBranchType(conditionalExpression.FalseExpr, fieldTypeDescriptor); isValueType {
+		return falseType
+	}
+
+	return FtInvalid
+}
+
+// FunctionCall represents an invocation of a registered function, e.g. LENGTH(summary)
+type FunctionCall struct {
+	name      *QueryToken
+	Arguments []Expression
+
+	// resolvedFunction is populated by ConvertTypes from the QueryContext's
+	// FunctionRegistry, so that Validate and Evaluate need not look it up again via
+	// a package-level registry that could belong to a different query by the time
+	// Evaluate runs
+	resolvedFunction *Function
+}
+
+// resolveFunction looks up this function call's definition via the FunctionRegistry
+// carried on fieldTypeDescriptor, which ExpressionProcessor always supplies as a QueryContext
+func (functionCall *FunctionCall) resolveFunction(fieldTypeDescriptor FieldTypeDescriptor) (function Function, exists bool) {
+	queryContext, ok := fieldTypeDescriptor.(QueryContext)
+	if !ok {
+		return
+	}
+
+	return queryContext.FunctionRegistry().Function(functionCall.name.value)
+}
+
+// IsBoolean returns true only if this function call resolves to a boolean value - unlike a
+// BinaryExpression or UnaryExpression, a FunctionCall's result type depends entirely on which
+// function is called (e.g. LENGTH returns a Number, CONTAINS returns a Bool)
+func (functionCall *FunctionCall) IsBoolean(fieldTypeDescriptor FieldTypeDescriptor) bool {
+	return functionCall.FieldType(fieldTypeDescriptor) == FtBool
+}
+
+// Equal returns true if the provided expression is equal
+func (functionCall *FunctionCall) Equal(expression Expression) bool {
+	other, ok := expression.(*FunctionCall)
+	if !ok || functionCall.name.value != other.name.value || len(functionCall.Arguments) != len(other.Arguments) {
+		return false
+	}
+
+	for index, argument := range functionCall.Arguments {
+		if !argument.Equal(other.Arguments[index]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the string representation of this function call
+func (functionCall *FunctionCall) String() string {
+	var arguments []string
+
+	for _, argument := range functionCall.Arguments {
+		if stringer, ok := argument.(fmt.Stringer); ok {
+			arguments = append(arguments, stringer.String())
 		}
+	}
 
-		if logicalExpression, ok := binaryExpression.rhs.(LogicalExpression); ok {
+	return fmt.Sprintf("%v(%v)", functionCall.name.value, strings.Join(arguments, ", "))
+}
+
+// Pos returns the position this function call appeared at in the input stream
+func (functionCall *FunctionCall) Pos() QueryScannerPos {
+	return functionCall.name.startPos
+}
+
+// FieldType returns the data type this function call resolves to
+func (functionCall *FunctionCall) FieldType(fieldTypeDescriptor FieldTypeDescriptor) FieldType {
+	if functionCall.resolvedFunction != nil {
+		return functionCall.resolvedFunction.ReturnType
+	}
+
+	if function, exists := functionCall.resolveFunction(fieldTypeDescriptor); exists {
+		return function.ReturnType
+	}
+
+	return FtInvalid
+}
+
+// ConvertTypes resolves and caches the called function and defers type conversion
+// to each argument expression
+func (functionCall *FunctionCall) ConvertTypes(fieldTypeDescriptor FieldTypeDescriptor) {
+	if function, exists := functionCall.resolveFunction(fieldTypeDescriptor); exists {
+		functionCall.resolvedFunction = &function
+	}
+
+	for _, argument := range functionCall.Arguments {
+		if logicalExpression, ok := argument.(LogicalExpression); ok {
 			logicalExpression.ConvertTypes(fieldTypeDescriptor)
 		}
+	}
+}
+
+// Validate checks the function is registered, the argument count matches and each
+// argument is valid and has exactly the function's declared argument type (arguments
+// are not coerced)
+func (functionCall *FunctionCall) Validate(fieldTypeDescriptor FieldTypeDescriptor) (errors []error) {
+	var function Function
+	var exists bool
+
+	if functionCall.resolvedFunction != nil {
+		function, exists = *functionCall.resolvedFunction, true
+	} else {
+		function, exists = functionCall.resolveFunction(fieldTypeDescriptor)
+	}
+
+	if !exists {
+		errors = append(errors, GenerateExpressionError(functionCall, "Unknown function: %v", functionCall.name.value))
+		return
+	}
 
+	if len(functionCall.Arguments) != len(function.ArgumentTypes) {
+		errors = append(errors, GenerateExpressionError(functionCall, "%v expects %v argument(s) but received %v",
+			functionCall.name.value, len(function.ArgumentTypes), len(functionCall.Arguments)))
+		return
+	}
+
+	for index, argument := range functionCall.Arguments {
+		if validatableExpression, ok := argument.(ValidatableExpression); ok {
+			errors = append(errors, validatableExpression.Validate(fieldTypeDescriptor)...)
+		}
+
+		argumentType, isValueType := determineFieldType(argument, fieldTypeDescriptor)
+		expectedType := function.ArgumentTypes[index]
+
+		if isValueType && !(argumentType == FtInvalid) && argumentType != expectedType {
+			errors = append(errors, GenerateExpressionError(argument, "Argument %v of %v has invalid type: %v. Expected type: %v",
+				index+1, functionCall.name.value, fieldTypeNames[argumentType], fieldTypeNames[expectedType]))
+		}
+	}
+
+	return
+}
+
+// DefaultFunctionRegistry provides the set of functions built into grv's query language
+type DefaultFunctionRegistry struct{}
+
+// NewDefaultFunctionRegistry creates a new instance of the built-in function registry
+func NewDefaultFunctionRegistry() DefaultFunctionRegistry {
+	return DefaultFunctionRegistry{}
+}
+
+// Function returns the definition of a built-in function
+func (DefaultFunctionRegistry) Function(name string) (function Function, exists bool) {
+	function, exists = builtinFunctions[strings.ToUpper(name)]
+	return
+}
+
+var builtinFunctions = map[string]Function{
+	"LENGTH": {
+		ArgumentTypes: []FieldType{FtString},
+		ReturnType:    FtNumber,
+		Execute: func(arguments []interface{}) (interface{}, error) {
+			value, ok := arguments[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("LENGTH requires a string argument")
+			}
+
+			return float64(len(value)), nil
+		},
+	},
+	"LOWER": {
+		ArgumentTypes: []FieldType{FtString},
+		ReturnType:    FtString,
+		Execute: func(arguments []interface{}) (interface{}, error) {
+			value, ok := arguments[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("LOWER requires a string argument")
+			}
+
+			return strings.ToLower(value), nil
+		},
+	},
+	"UPPER": {
+		ArgumentTypes: []FieldType{FtString},
+		ReturnType:    FtString,
+		Execute: func(arguments []interface{}) (interface{}, error) {
+			value, ok := arguments[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("UPPER requires a string argument")
+			}
+
+			return strings.ToUpper(value), nil
+		},
+	},
+	"CONTAINS": {
+		ArgumentTypes: []FieldType{FtString, FtString},
+		ReturnType:    FtBool,
+		Execute: func(arguments []interface{}) (interface{}, error) {
+			value, ok := arguments[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("CONTAINS requires string arguments")
+			}
+
+			substr, ok := arguments[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("CONTAINS requires string arguments")
+			}
+
+			return strings.Contains(value, substr), nil
+		},
+	},
+	"AGE": {
+		ArgumentTypes: []FieldType{FtDate},
+		ReturnType:    FtDuration,
+		Execute: func(arguments []interface{}) (interface{}, error) {
+			value, ok := arguments[0].(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("AGE requires a date argument")
+			}
+
+			return time.Now().Sub(value), nil
+		},
+	},
+}
+
+// ConvertTypes defers the call to the child expressions if they're logical, then -
+// for comparison operators - runs the string literal coercion passes below. The
+// deferred call happens unconditionally, since a comparison operand such as a
+// FunctionCall still needs ConvertTypes called on it to resolve and cache its
+// function before Validate/Evaluate run
+func (binaryExpression *BinaryExpression) ConvertTypes(fieldTypeDescriptor FieldTypeDescriptor) {
+	if logicalExpression, ok := binaryExpression.lhs.(LogicalExpression); ok {
+		logicalExpression.ConvertTypes(fieldTypeDescriptor)
+	}
+
+	if logicalExpression, ok := binaryExpression.rhs.(LogicalExpression); ok {
+		logicalExpression.ConvertTypes(fieldTypeDescriptor)
+	}
+
+	if !binaryExpression.IsComparison() {
 		return
 	}
 
 	binaryExpression.processDateComparison(fieldTypeDescriptor)
+	binaryExpression.processNowDurationComparison(fieldTypeDescriptor)
+	binaryExpression.processDurationComparison(fieldTypeDescriptor)
+	binaryExpression.processNumberComparison(fieldTypeDescriptor)
+	binaryExpression.processBoolComparison(fieldTypeDescriptor)
 	binaryExpression.processGlobComparison(fieldTypeDescriptor)
 	binaryExpression.processRegexComparison(fieldTypeDescriptor)
+	binaryExpression.processSetComparison(fieldTypeDescriptor)
 }
 
-func (binaryExpression *BinaryExpression) processDateComparison(fieldTypeDescriptor FieldTypeDescriptor) {
-	isDateComparison, dateString, datePtr := binaryExpression.isDateComparison(fieldTypeDescriptor)
-	if !isDateComparison {
+// numberPattern matches string literals that can be implicitly widened to a NumberLiteral
+var numberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// convertStringToNumber attempts to parse a string literal as a number, returning
+// the equivalent NumberLiteral if the string is numeric
+func convertStringToNumber(numberString *StringLiteral) (numberLiteral *NumberLiteral, converted bool) {
+	if !numberPattern.MatchString(numberString.value.value) {
+		return
+	}
+
+	numberLiteral = &NumberLiteral{
+		value: numberString.value,
+	}
+	converted = true
+
+	return
+}
+
+// convertStringToDuration attempts to parse a string literal as a duration, returning
+// the equivalent DurationLiteral if the string matches the supported duration format
+func convertStringToDuration(durationString *StringLiteral) (durationLiteral *DurationLiteral, converted bool) {
+	duration, err := ParseDuration(durationString.value.value)
+	if err != nil {
+		return
+	}
+
+	durationLiteral = &DurationLiteral{
+		duration:      duration,
+		durationToken: durationString.value,
+	}
+	converted = true
+
+	return
+}
+
+// convertStringToBool attempts to parse a string literal as a boolean, returning
+// the equivalent BoolLiteral if the string is "true" or "false"
+func convertStringToBool(boolString *StringLiteral) (boolLiteral *BoolLiteral, converted bool) {
+	switch strings.ToLower(boolString.value.value) {
+	case "true":
+		boolLiteral = &BoolLiteral{value: true, boolString: boolString.value}
+	case "false":
+		boolLiteral = &BoolLiteral{value: false, boolString: boolString.value}
+	default:
+		return
+	}
+
+	converted = true
+
+	return
+}
+
+// isTypedFieldStringComparison reports whether this binary expression compares an Identifier
+// of the given field type against a StringLiteral, regardless of which side of the operator
+// each operand appears on. It returns a pointer to the StringLiteral's operand slot so callers
+// can replace it in place with a coerced literal
+func (binaryExpression *BinaryExpression) isTypedFieldStringComparison(fieldTypeDescriptor FieldTypeDescriptor, requiredFieldType FieldType) (matches bool, stringLiteral *StringLiteral, operandPtr *Expression) {
+	identifier, ok := binaryExpression.lhs.(*Identifier)
+
+	if ok {
+		stringLiteral, _ = binaryExpression.rhs.(*StringLiteral)
+		operandPtr = &binaryExpression.rhs
+	} else {
+		stringLiteral, _ = binaryExpression.lhs.(*StringLiteral)
+		identifier, _ = binaryExpression.rhs.(*Identifier)
+		operandPtr = &binaryExpression.lhs
+	}
+
+	if identifier == nil || stringLiteral == nil {
 		return
 	}
 
+	fieldType, fieldExists := fieldTypeDescriptor.FieldType(identifier.identifier.value)
+	if !fieldExists || fieldType != requiredFieldType {
+		return
+	}
+
+	matches = true
+
+	return
+}
+
+// processNumberComparison implicitly widens a numeric-looking string literal being
+// compared against a number field into a NumberLiteral
+func (binaryExpression *BinaryExpression) processNumberComparison(fieldTypeDescriptor FieldTypeDescriptor) {
+	isNumberComparison, numberString, numberPtr := binaryExpression.isNumberComparison(fieldTypeDescriptor)
+	if !isNumberComparison {
+		return
+	}
+
+	if numberLiteral, converted := convertStringToNumber(numberString); converted {
+		*numberPtr = numberLiteral
+	}
+}
+
+func (binaryExpression *BinaryExpression) isNumberComparison(fieldTypeDescriptor FieldTypeDescriptor) (isNumberComparison bool, numberString *StringLiteral, numberPtr *Expression) {
+	isNumberComparison, numberString, numberPtr = binaryExpression.isTypedFieldStringComparison(fieldTypeDescriptor, FtNumber)
+	return
+}
+
+// processBoolComparison implicitly widens a "true"/"false" string literal being
+// compared against a bool field into a BoolLiteral
+func (binaryExpression *BinaryExpression) processBoolComparison(fieldTypeDescriptor FieldTypeDescriptor) {
+	isBoolComparison, boolString, boolPtr := binaryExpression.isBoolComparison(fieldTypeDescriptor)
+	if !isBoolComparison {
+		return
+	}
+
+	if boolLiteral, converted := convertStringToBool(boolString); converted {
+		*boolPtr = boolLiteral
+	}
+}
+
+func (binaryExpression *BinaryExpression) isBoolComparison(fieldTypeDescriptor FieldTypeDescriptor) (isBoolComparison bool, boolString *StringLiteral, boolPtr *Expression) {
+	isBoolComparison, boolString, boolPtr = binaryExpression.isTypedFieldStringComparison(fieldTypeDescriptor, FtBool)
+	return
+}
+
+// convertStringToDate attempts to parse a string literal as a date, returning
+// the equivalent DateLiteral if the string matches one of the supported date formats
+func convertStringToDate(dateString *StringLiteral) (dateLiteral *DateLiteral, converted bool) {
 	var dateFormat string
 
 	switch {
@@ -376,28 +1112,97 @@ func (binaryExpression *BinaryExpression) processDateComparison(fieldTypeDescrip
 	dateTime := time.Date(utcDateTime.Year(), utcDateTime.Month(), utcDateTime.Day(), utcDateTime.Hour(),
 		utcDateTime.Minute(), utcDateTime.Second(), utcDateTime.Nanosecond(), time.Local)
 
-	*datePtr = &DateLiteral{
+	dateLiteral = &DateLiteral{
 		dateTime:   dateTime,
 		stringTime: dateString.value,
 	}
+	converted = true
+
+	return
+}
+
+func (binaryExpression *BinaryExpression) processDateComparison(fieldTypeDescriptor FieldTypeDescriptor) {
+	isDateComparison, dateString, datePtr := binaryExpression.isDateComparison(fieldTypeDescriptor)
+	if !isDateComparison {
+		return
+	}
+
+	if dateLiteral, converted := convertStringToDate(dateString); converted {
+		*datePtr = dateLiteral
+	}
 }
 
 func (binaryExpression *BinaryExpression) isDateComparison(fieldTypeDescriptor FieldTypeDescriptor) (isDateComparison bool, dateString *StringLiteral, datePtr *Expression) {
+	isDateComparison, dateString, datePtr = binaryExpression.isTypedFieldStringComparison(fieldTypeDescriptor, FtDate)
+	return
+}
+
+// processDurationComparison implicitly widens a duration-looking string literal, e.g. "7d",
+// being compared against a duration field into a DurationLiteral
+func (binaryExpression *BinaryExpression) processDurationComparison(fieldTypeDescriptor FieldTypeDescriptor) {
+	isDurationComparison, durationString, durationPtr := binaryExpression.isDurationComparison(fieldTypeDescriptor)
+	if !isDurationComparison {
+		return
+	}
+
+	if durationLiteral, converted := convertStringToDuration(durationString); converted {
+		*durationPtr = durationLiteral
+	}
+}
+
+func (binaryExpression *BinaryExpression) isDurationComparison(fieldTypeDescriptor FieldTypeDescriptor) (isDurationComparison bool, durationString *StringLiteral, durationPtr *Expression) {
+	isDurationComparison, durationString, durationPtr = binaryExpression.isTypedFieldStringComparison(fieldTypeDescriptor, FtDuration)
+	return
+}
+
+// processNowDurationComparison folds a comparison between a date field and a
+// now +/- duration arithmetic expression into a concrete DateLiteral, using the
+// evaluation time captured for the current Process call
+func (binaryExpression *BinaryExpression) processNowDurationComparison(fieldTypeDescriptor FieldTypeDescriptor) {
+	isNowDurationComparison, nowExpr, datePtr := binaryExpression.isNowDurationComparison(fieldTypeDescriptor)
+	if !isNowDurationComparison {
+		return
+	}
+
+	queryContext, ok := fieldTypeDescriptor.(QueryContext)
+	if !ok {
+		return
+	}
+
+	durationLiteral := nowExpr.rhs.(*DurationLiteral)
+	dateTime := queryContext.EvaluationTime()
+
+	switch nowExpr.operator.operator.tokenType {
+	case QtkMinus:
+		dateTime = dateTime.Add(-durationLiteral.duration)
+	case QtkPlus:
+		dateTime = dateTime.Add(durationLiteral.duration)
+	default:
+		return
+	}
+
+	*datePtr = &DateLiteral{
+		dateTime:   dateTime,
+		stringTime: durationLiteral.durationToken,
+	}
+}
+
+func (binaryExpression *BinaryExpression) isNowDurationComparison(fieldTypeDescriptor FieldTypeDescriptor) (isNowDurationComparison bool, nowExpr *BinaryExpression, datePtr *Expression) {
 	var identifier *Identifier
 	var ok bool
 
 	identifier, ok = binaryExpression.lhs.(*Identifier)
 
 	if ok {
-		dateString, _ = binaryExpression.rhs.(*StringLiteral)
+		nowExpr, _ = binaryExpression.rhs.(*BinaryExpression)
 		datePtr = &binaryExpression.rhs
 	} else {
-		dateString, _ = binaryExpression.lhs.(*StringLiteral)
+		nowExpr, _ = binaryExpression.lhs.(*BinaryExpression)
 		identifier, _ = binaryExpression.rhs.(*Identifier)
 		datePtr = &binaryExpression.lhs
 	}
 
-	if identifier == nil || dateString == nil {
+	if identifier == nil || nowExpr == nil {
 		return
 	}
 
@@ -406,7 +1211,16 @@ func (binaryExpression *BinaryExpression) isDateComparison(fieldTypeDescriptor F
 		return
 	}
 
-	isDateComparison = true
+	nowIdentifier, ok := nowExpr.lhs.(*Identifier)
+	if !ok || nowIdentifier.identifier.value != nowIdentifierValue {
+		return
+	}
+
+	if _, ok := nowExpr.rhs.(*DurationLiteral); !ok {
+		return
+	}
+
+	isNowDurationComparison = true
 
 	return
 }
@@ -433,27 +1247,7 @@ func (binaryExpression *BinaryExpression) isGlobComparison(fieldTypeDescriptor F
 		return
 	}
 
-	identifier, ok := binaryExpression.lhs.(*Identifier)
-
-	if ok {
-		globString, _ = binaryExpression.rhs.(*StringLiteral)
-		globPtr = &binaryExpression.rhs
-	} else {
-		globString, _ = binaryExpression.lhs.(*StringLiteral)
-		identifier, _ = binaryExpression.rhs.(*Identifier)
-		globPtr = &binaryExpression.lhs
-	}
-
-	if identifier == nil || globString == nil {
-		return
-	}
-
-	fieldType, fieldExists := fieldTypeDescriptor.FieldType(identifier.identifier.value)
-	if !fieldExists || fieldType != FtString {
-		return
-	}
-
-	isGlobComparison = true
+	isGlobComparison, globString, globPtr = binaryExpression.isTypedFieldStringComparison(fieldTypeDescriptor, FtString)
 
 	return
 }
@@ -480,27 +1274,79 @@ func (binaryExpression *BinaryExpression) isRegexComparison(fieldTypeDescriptor
 		return
 	}
 
-	identifier, ok := binaryExpression.lhs.(*Identifier)
+	isRegexComparison, regexString, regexPtr = binaryExpression.isTypedFieldStringComparison(fieldTypeDescriptor, FtString)
 
-	if ok {
-		regexString, _ = binaryExpression.rhs.(*StringLiteral)
-		regexPtr = &binaryExpression.rhs
-	} else {
-		regexString, _ = binaryExpression.lhs.(*StringLiteral)
-		identifier, _ = binaryExpression.rhs.(*Identifier)
-		regexPtr = &binaryExpression.lhs
+	return
+}
+
+// processSetComparison converts each element of a SetLiteral RHS to the LHS field type
+// where a conversion is available, reusing the same string literal conversion functions
+// used for plain date/number/bool/duration comparisons
+func (binaryExpression *BinaryExpression) processSetComparison(fieldTypeDescriptor FieldTypeDescriptor) {
+	if !binaryExpression.operator.isSetOperator() {
+		return
+	}
+
+	identifier, ok := binaryExpression.lhs.(*Identifier)
+	if !ok {
+		return
 	}
 
-	if identifier == nil || regexString == nil {
+	setLiteral, ok := binaryExpression.rhs.(*SetLiteral)
+	if !ok {
 		return
 	}
 
 	fieldType, fieldExists := fieldTypeDescriptor.FieldType(identifier.identifier.value)
-	if !fieldExists || fieldType != FtString {
+	if !fieldExists {
 		return
 	}
 
-	isRegexComparison = true
+	for index, value := range setLiteral.values {
+		stringLiteral, ok := value.(*StringLiteral)
+		if !ok {
+			continue
+		}
+
+		switch fieldType {
+		case FtDate:
+			if dateLiteral, converted := convertStringToDate(stringLiteral); converted {
+				setLiteral.values[index] = dateLiteral
+			}
+		case FtNumber:
+			if numberLiteral, converted := convertStringToNumber(stringLiteral); converted {
+				setLiteral.values[index] = numberLiteral
+			}
+		case FtBool:
+			if boolLiteral, converted := convertStringToBool(stringLiteral); converted {
+				setLiteral.values[index] = boolLiteral
+			}
+		case FtDuration:
+			if durationLiteral, converted := convertStringToDuration(stringLiteral); converted {
+				setLiteral.values[index] = durationLiteral
+			}
+		}
+	}
+}
+
+// validateSetComparison checks that every element of a SetLiteral RHS is coercible to the LHS field type
+func (binaryExpression *BinaryExpression) validateSetComparison(fieldTypeDescriptor FieldTypeDescriptor, lhsType FieldType) (errors []error) {
+	setLiteral, ok := binaryExpression.rhs.(*SetLiteral)
+	if !ok {
+		return
+	}
+
+	for _, value := range setLiteral.values {
+		typeDescriptor, ok := value.(TypeDescriptor)
+		if !ok {
+			continue
+		}
+
+		if valueType := typeDescriptor.FieldType(fieldTypeDescriptor); valueType != lhsType {
+			errors = append(errors, GenerateExpressionError(value, "Set element has invalid type: %v. Expected type: %v",
+				fieldTypeNames[valueType], fieldTypeNames[lhsType]))
+		}
+	}
 
 	return
 }
@@ -508,16 +1354,16 @@ func (binaryExpression *BinaryExpression) isRegexComparison(fieldTypeDescriptor
 // Validate the child expressions and operator are valid
 func (binaryExpression *BinaryExpression) Validate(fieldTypeDescriptor FieldTypeDescriptor) (errors []error) {
 	if !binaryExpression.IsComparison() {
-		if logicalExpression, ok := binaryExpression.lhs.(LogicalExpression); !ok {
+		if !isExpressionBoolean(binaryExpression.lhs, fieldTypeDescriptor) {
 			errors = append(errors, GenerateExpressionError(binaryExpression, "Operands of a logical operator must resolve to boolean values"))
-		} else {
-			errors = append(errors, logicalExpression.Validate(fieldTypeDescriptor)...)
+		} else if validatableExpression, ok := binaryExpression.lhs.(ValidatableExpression); ok {
+			errors = append(errors, validatableExpression.Validate(fieldTypeDescriptor)...)
 		}
 
-		if logicalExpression, ok := binaryExpression.rhs.(LogicalExpression); !ok {
+		if !isExpressionBoolean(binaryExpression.rhs, fieldTypeDescriptor) {
 			errors = append(errors, GenerateExpressionError(binaryExpression, "Operands of a logical operator must resolve to boolean values"))
-		} else {
-			errors = append(errors, logicalExpression.Validate(fieldTypeDescriptor)...)
+		} else if validatableExpression, ok := binaryExpression.rhs.(ValidatableExpression); ok {
+			errors = append(errors, validatableExpression.Validate(fieldTypeDescriptor)...)
 		}
 
 		return
@@ -547,6 +1393,10 @@ func (binaryExpression *BinaryExpression) Validate(fieldTypeDescriptor FieldType
 				errors = append(errors, GenerateExpressionError(binaryExpression, "Argument on RHS has invalid type: %v. Allowed types are: %v",
 					fieldTypeNames[rhsType], fieldTypeNamesString(binaryExpression.operator.allowedTypes(bopRight))))
 			}
+
+			if binaryExpression.operator.isSetOperator() {
+				errors = append(errors, binaryExpression.validateSetComparison(fieldTypeDescriptor, lhsType)...)
+			}
 		}
 	} else if lhsType != rhsType && !(lhsType == FtInvalid || rhsType == FtInvalid) {
 		errors = append(errors, GenerateExpressionError(binaryExpression, "Attempting to compare different types - LHS Type: %v vs RHS Type: %v",
@@ -556,6 +1406,12 @@ func (binaryExpression *BinaryExpression) Validate(fieldTypeDescriptor FieldType
 	return
 }
 
+// IsBoolean returns true, since both logical (AND/OR) and comparison expressions always
+// resolve to a boolean value
+func (binaryExpression *BinaryExpression) IsBoolean(fieldTypeDescriptor FieldTypeDescriptor) bool {
+	return true
+}
+
 func determineFieldType(expression Expression, fieldTypeDescriptor FieldTypeDescriptor) (fieldType FieldType, isValueType bool) {
 	if typeDescriptor, ok := expression.(TypeDescriptor); ok {
 		fieldType = typeDescriptor.FieldType(fieldTypeDescriptor)